@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Progress is the structured result of parsing one line of a backup tool's
+// output: bytes transferred, an ETA, and files done/total when the tool
+// reports them. Zero values mean "not reported by this line".
+type Progress struct {
+	BytesDone  int64
+	BytesTotal int64
+	FilesDone  int
+	FilesTotal int
+	ETA        time.Duration
+	Err        string
+}
+
+// Fraction returns a 0..1 completion ratio for bubbles/progress, preferring
+// bytes over file counts since most tools report bytes more precisely.
+func (p Progress) Fraction() float64 {
+	switch {
+	case p.BytesTotal > 0:
+		return float64(p.BytesDone) / float64(p.BytesTotal)
+	case p.FilesTotal > 0:
+		return float64(p.FilesDone) / float64(p.FilesTotal)
+	default:
+		return 0
+	}
+}
+
+// BackupParser turns one line of a backup tool's log output into a
+// Progress update. ok is false for lines that carry nothing to report.
+type BackupParser interface {
+	Parse(line string) (p Progress, ok bool)
+}
+
+// builtinParsers maps a substring of a unit's ExecStart to the parser that
+// understands its progress output.
+var builtinParsers = map[string]BackupParser{
+	"rclone": rcloneParser{},
+	"restic": resticParser{},
+	"borg":   borgParser{},
+}
+
+// SelectParser picks a parser for a unit by checking whether a known
+// backup tool's binary name appears in its ExecStart, then falling back to
+// any regex parsers the user registered in the config file.
+func SelectParser(execStart string, extra []ParserRule) BackupParser {
+	for name, p := range builtinParsers {
+		if strings.Contains(execStart, name) {
+			return p
+		}
+	}
+	for _, rule := range extra {
+		if strings.Contains(execStart, rule.Match) {
+			if p, ok := newRegexParser(rule); ok {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// ---------- rclone ----------
+
+type rcloneParser struct{}
+
+// Transferred:   	  123.456 MiB / 1.234 GiB, 10%, 4.567 MiB/s, ETA 1m23s
+var rcloneLine = regexp.MustCompile(`Transferred:\s+([\d.]+\s*\w+) / ([\d.]+\s*\w+), \d+%.*ETA (\S+)`)
+
+func (rcloneParser) Parse(line string) (Progress, bool) {
+	m := rcloneLine.FindStringSubmatch(line)
+	if m == nil {
+		return Progress{}, false
+	}
+	return Progress{
+		BytesDone:  parseSize(m[1]),
+		BytesTotal: parseSize(m[2]),
+		ETA:        parseETA(m[3]),
+	}, true
+}
+
+// ---------- restic ----------
+
+type resticParser struct{}
+
+// [0:05] 42.17%  123.456 MiB/s  1.234 GiB / 2.345 GiB  12 / 42 items  0 errors  ETA 0:12
+var resticLine = regexp.MustCompile(`\[\d+:\d+\]\s+[\d.]+%.*?([\d.]+\s*\w+) / ([\d.]+\s*\w+)\s+(\d+) / (\d+) items`)
+
+func (resticParser) Parse(line string) (Progress, bool) {
+	m := resticLine.FindStringSubmatch(line)
+	if m == nil {
+		return Progress{}, false
+	}
+	filesDone, _ := strconv.Atoi(m[3])
+	filesTotal, _ := strconv.Atoi(m[4])
+	return Progress{
+		BytesDone:  parseSize(m[1]),
+		BytesTotal: parseSize(m[2]),
+		FilesDone:  filesDone,
+		FilesTotal: filesTotal,
+	}, true
+}
+
+// ---------- borg ----------
+
+type borgParser struct{}
+
+// 12.34 GB O 23.45 GB C 1234 N  path/to/current/file
+var borgLine = regexp.MustCompile(`([\d.]+\s*\w+) O ([\d.]+\s*\w+) C (\d+) N`)
+
+func (borgParser) Parse(line string) (Progress, bool) {
+	m := borgLine.FindStringSubmatch(line)
+	if m == nil {
+		return Progress{}, false
+	}
+	filesDone, _ := strconv.Atoi(m[3])
+	return Progress{
+		BytesDone: parseSize(m[1]),
+		FilesDone: filesDone,
+	}, true
+}
+
+// ---------- user-registered regex parsers ----------
+
+// ParserRule lets a config file teach the dashboard a new backup tool:
+// Match is the ExecStart substring that selects it, and Pattern is a
+// regexp whose named capture groups ("bytes_done", "bytes_total",
+// "files_done", "files_total") are matched by name onto Progress fields
+// in regexParser.Parse.
+type ParserRule struct {
+	Match   string `toml:"match"`
+	Pattern string `toml:"pattern"`
+}
+
+type regexParser struct {
+	re *regexp.Regexp
+}
+
+func newRegexParser(rule ParserRule) (regexParser, bool) {
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return regexParser{}, false
+	}
+	return regexParser{re: re}, true
+}
+
+func (p regexParser) Parse(line string) (Progress, bool) {
+	m := p.re.FindStringSubmatch(line)
+	if m == nil {
+		return Progress{}, false
+	}
+	var prog Progress
+	for i, name := range p.re.SubexpNames() {
+		if i == 0 || name == "" || i >= len(m) {
+			continue
+		}
+		switch name {
+		case "bytes_done":
+			prog.BytesDone = parseSize(m[i])
+		case "bytes_total":
+			prog.BytesTotal = parseSize(m[i])
+		case "files_done":
+			prog.FilesDone, _ = strconv.Atoi(m[i])
+		case "files_total":
+			prog.FilesTotal, _ = strconv.Atoi(m[i])
+		}
+	}
+	return prog, true
+}
+
+// getExecStart reads a unit's configured command line via "systemctl show",
+// used to pick a BackupParser before the job's log lines start arriving.
+func getExecStart(ctx context.Context, unit UnitConfig) (string, error) {
+	args := []string{}
+	if unit.Scope == ScopeUser {
+		args = append(args, "--user")
+	}
+	args = append(args, "show", unit.Unit, "--property=ExecStart")
+	out, err := exec.CommandContext(ctx, "systemctl", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ---------- shared helpers ----------
+
+var sizeUnits = map[string]int64{
+	"B": 1, "KIB": 1 << 10, "MIB": 1 << 20, "GIB": 1 << 30, "TIB": 1 << 40,
+	"KB": 1e3, "MB": 1e6, "GB": 1e9, "TB": 1e12,
+}
+
+// parseSize reads a "123.4 MiB"-style quantity as printed by rclone,
+// restic and borg into a byte count.
+func parseSize(s string) int64 {
+	fields := strings.Fields(strings.TrimSpace(s))
+	if len(fields) != 2 {
+		return 0
+	}
+	n, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	unit, ok := sizeUnits[strings.ToUpper(fields[1])]
+	if !ok {
+		return 0
+	}
+	return int64(n * float64(unit))
+}
+
+// parseETA reads either rclone's "1m23s"-style duration or a restic
+// "H:MM:SS" / "M:SS" clock into a time.Duration.
+func parseETA(s string) time.Duration {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d
+	}
+	parts := strings.Split(s, ":")
+	var secs int64
+	for _, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0
+		}
+		secs = secs*60 + n
+	}
+	return time.Duration(secs) * time.Second
+}
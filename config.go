@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Scope controls whether a unit is driven through system systemd or the
+// calling user's session (systemctl --user).
+type Scope string
+
+const (
+	ScopeSystem Scope = "system"
+	ScopeUser   Scope = "user"
+)
+
+// ActionDef is one key-triggered operation on a unit. Verb is one of
+// status, start, enable, toggle (enable or disable depending on the unit's
+// current state) or logs (read through journalctl); any other verb is
+// rejected as an unknown action. Pre and
+// Post, when set, are run with "sh -c" before/after the systemctl call so a
+// user can e.g. lock a mount point before a restic prune.
+type ActionDef struct {
+	Verb string `toml:"verb"`
+	Pre  string `toml:"pre"`
+	Post string `toml:"post"`
+}
+
+// UnitConfig declares a single systemd unit the TUI should manage.
+type UnitConfig struct {
+	Display        string               `toml:"display"`
+	Description    string               `toml:"description"`
+	Unit           string               `toml:"unit"`
+	Scope          Scope                `toml:"scope"`
+	Actions        map[string]ActionDef `toml:"actions"`
+	UpdateInterval string               `toml:"update_interval"` // e.g. "30s"; empty disables polling
+}
+
+// PollInterval parses UpdateInterval, returning 0 (meaning "don't poll")
+// when it is unset.
+func (u UnitConfig) PollInterval() (time.Duration, error) {
+	if u.UpdateInterval == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(u.UpdateInterval)
+}
+
+// Config is the top-level ~/.config/backup_tui/units.toml document.
+type Config struct {
+	Units             []UnitConfig `toml:"unit"`
+	Parsers           []ParserRule `toml:"parser"`
+	PreferSudoAskpass bool         `toml:"prefer_sudo_askpass"`
+}
+
+func defaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backup_tui", "units.toml"), nil
+}
+
+// LoadConfig reads the unit list from path, or from the default
+// ~/.config/backup_tui/units.toml when path is empty. If no config file
+// exists yet it falls back to the legacy hard-coded OneDrive unit pair so
+// existing installs keep working without having to write one first.
+func LoadConfig(path string) (Config, error) {
+	if path == "" {
+		p, err := defaultConfigPath()
+		if err != nil {
+			return Config{}, err
+		}
+		path = p
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return legacyConfig(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if _, err := toml.Decode(string(data), &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(cfg.Units) == 0 {
+		return Config{}, fmt.Errorf("%s declares no units", path)
+	}
+	for i := range cfg.Units {
+		if cfg.Units[i].Scope == "" {
+			cfg.Units[i].Scope = ScopeSystem
+		}
+	}
+	return cfg, nil
+}
+
+// legacyConfig reproduces the unit list this tool used to hard-code before
+// units.toml existed, so an upgrade with no config present behaves the same.
+func legacyConfig() Config {
+	return Config{Units: []UnitConfig{
+		{
+			Display:     timerName,
+			Description: "Enable/Disable, View status, Run Now",
+			Unit:        timerName,
+			Scope:       ScopeSystem,
+			Actions: map[string]ActionDef{
+				"enter": {Verb: "status"},
+				" ":     {Verb: "toggle"},
+				"r":     {Verb: "start"},
+				"l":     {Verb: "logs"},
+			},
+		},
+		{
+			Display:     serviceName,
+			Description: "Run Now, View logs",
+			Unit:        serviceName,
+			Scope:       ScopeSystem,
+			Actions: map[string]ActionDef{
+				"enter": {Verb: "status"},
+				" ":     {Verb: "enable"},
+				"r":     {Verb: "start"},
+				"l":     {Verb: "logs"},
+			},
+		},
+	}}
+}
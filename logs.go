@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// logRingSize bounds how many lines the follow view keeps in memory.
+const logRingSize = 500
+
+// logLineMsg carries one line read from a running journalctl -f, tagged
+// with the stream it came from so a stale stream can't write into a newer
+// one after the user switches units. closed is set once the stream's
+// channel is drained (process exited or was cancelled).
+type logLineMsg struct {
+	streamID int
+	line     string
+	closed   bool
+	err      error
+}
+
+// logStream wraps a live "journalctl -f" follow for a single unit, with the
+// scrollback rendered through a bubbles/viewport.
+type logStream struct {
+	id     int
+	unit   string
+	cancel context.CancelFunc
+	lines  chan logLineMsg
+	vp     viewport.Model
+	buf    []string
+
+	// parser and bar are set by startBackupFollow when the unit's
+	// ExecStart matched a known (or config-registered) backup tool; they
+	// stay nil for a plain log follow/tail.
+	parser BackupParser
+	bar    progress.Model
+	stats  Progress
+}
+
+var (
+	errStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#d75f5f"))
+	warnStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#d7af00"))
+
+	errPattern  = regexp.MustCompile(`(?i)\berror\b`)
+	warnPattern = regexp.MustCompile(`(?i)\bwarn(ing)?\b`)
+)
+
+// startLogStream launches "journalctl -u <unit> -f" in the background and
+// returns the new logStream plus the tea.Cmd that kicks off reading its
+// first line. Extend the existing single cancelFunc pattern: this stream
+// gets its own context/cancel independent of the rest of the model.
+func startLogStream(id int, unit UnitConfig) (*logStream, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ls := &logStream{
+		id:     id,
+		unit:   unit.Unit,
+		cancel: cancel,
+		lines:  make(chan logLineMsg, 64),
+		vp:     viewport.New(78, 20),
+	}
+
+	args := []string{}
+	if unit.Scope == ScopeUser {
+		args = append(args, "--user")
+	}
+	args = append(args, "-u", unit.Unit, "-f", "--output=short-iso")
+
+	// This goroutine is ls.lines' only writer, so it alone closes the
+	// channel once it's done sending — on any exit path, including a
+	// cancelled ctx — rather than leaving waitForLine's pending receive
+	// racing ctx.Done() and potentially blocking forever.
+	go func() {
+		defer close(ls.lines)
+		cmd := exec.CommandContext(ctx, "journalctl", args...)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			sendLogLine(ctx, ls.lines, logLineMsg{streamID: id, err: err, closed: true})
+			return
+		}
+		if err := cmd.Start(); err != nil {
+			sendLogLine(ctx, ls.lines, logLineMsg{streamID: id, err: err, closed: true})
+			return
+		}
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if !sendLogLine(ctx, ls.lines, logLineMsg{streamID: id, line: scanner.Text()}) {
+				return
+			}
+		}
+		_ = cmd.Wait()
+	}()
+
+	return ls, ls.waitForLine()
+}
+
+// startBackupFollow is startLogStream plus a BackupParser: as lines arrive
+// they're also run through parser to drive a bubbles/progress bar and a
+// running stats block rendered above the log pane.
+func startBackupFollow(id int, unit UnitConfig, parser BackupParser) (*logStream, tea.Cmd) {
+	ls, cmd := startLogStream(id, unit)
+	ls.parser = parser
+	ls.bar = progress.New(progress.WithDefaultGradient())
+	return ls, cmd
+}
+
+// sendLogLine delivers msg to lines unless ctx is cancelled first, so the
+// goroutine reading a stopped stream's output doesn't block forever once
+// nothing is left to drain its channel. Returns false if ctx won the race.
+func sendLogLine(ctx context.Context, lines chan<- logLineMsg, msg logLineMsg) bool {
+	select {
+	case lines <- msg:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// waitForLine returns a tea.Cmd that blocks on the stream's channel for the
+// next line. Update must call this again after receiving each logLineMsg
+// to keep the stream flowing, and stop calling it once closed is true. A
+// closed channel (the stream ended, or stop() cancelled it) unblocks this
+// receive deterministically and is reported as closed:true.
+func (ls *logStream) waitForLine() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ls.lines
+		if !ok {
+			return logLineMsg{streamID: ls.id, closed: true}
+		}
+		return msg
+	}
+}
+
+// appendLine pushes a freshly read line into the ring buffer, colorizing it
+// by severity, and refreshes the viewport content. When the stream has a
+// BackupParser attached, it also tries to parse the raw line into a
+// Progress update, returning the tea.Cmd that animates the bar to its new
+// percentage (nil if the line carried nothing to report).
+func (ls *logStream) appendLine(line string) tea.Cmd {
+	ls.buf = append(ls.buf, colorizeLogLine(line))
+	if len(ls.buf) > logRingSize {
+		ls.buf = ls.buf[len(ls.buf)-logRingSize:]
+	}
+	ls.vp.SetContent(strings.Join(ls.buf, "\n"))
+	ls.vp.GotoBottom()
+
+	if ls.parser == nil {
+		return nil
+	}
+	p, ok := ls.parser.Parse(line)
+	if !ok {
+		return nil
+	}
+	ls.stats = p
+	return ls.bar.SetPercent(p.Fraction())
+}
+
+func colorizeLogLine(line string) string {
+	switch {
+	case errPattern.MatchString(line):
+		return errStyle.Render(line)
+	case warnPattern.MatchString(line):
+		return warnStyle.Render(line)
+	default:
+		return line
+	}
+}
+
+// statsLine renders the running stats block shown above the log pane for
+// a backup-parser-backed stream; empty when no BackupParser is attached.
+// Until the first line parses, it shows the bar at 0%.
+func (ls *logStream) statsLine() string {
+	if ls.parser == nil {
+		return ""
+	}
+	s := ls.stats
+	line := ls.bar.View()
+	switch {
+	case s.BytesTotal > 0:
+		line += fmt.Sprintf("  %s / %s", formatBytes(s.BytesDone), formatBytes(s.BytesTotal))
+	case s.FilesTotal > 0:
+		line += fmt.Sprintf("  %d / %d files", s.FilesDone, s.FilesTotal)
+	}
+	if s.ETA > 0 {
+		line += "  ETA " + s.ETA.String()
+	}
+	return line
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for n/div >= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// stop cancels the underlying journalctl process for this stream.
+func (ls *logStream) stop() {
+	if ls.cancel != nil {
+		ls.cancel()
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,7 +11,9 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -22,11 +25,41 @@ const (
 
 type unitItem struct {
 	title, desc string
+	cfg         UnitConfig
 }
+
 func (i unitItem) Title() string       { return i.title }
 func (i unitItem) Description() string { return i.desc }
 func (i unitItem) FilterValue() string { return i.title }
 
+// execStartMsg carries the ExecStart line looked up for a just-started
+// unit, used to pick a BackupParser before its log lines start arriving.
+type execStartMsg struct {
+	unit      UnitConfig
+	execStart string
+}
+
+// lookupExecStart queries systemctl for a unit's ExecStart so the caller
+// can select a BackupParser before tailing its log.
+func lookupExecStart(unit UnitConfig) tea.Cmd {
+	return func() tea.Msg {
+		execStart, err := getExecStart(context.Background(), unit)
+		if err != nil {
+			return execStartMsg{unit: unit}
+		}
+		return execStartMsg{unit: unit, execStart: execStart}
+	}
+}
+
+// escalationNeededMsg signals that a systemctl call failed for what looks
+// like a permissions reason, and should be retried through escalatedCmd
+// (pkexec or sudo for system units, systemctl --user for user units).
+type escalationNeededMsg struct {
+	unit   UnitConfig
+	args   []string
+	action ActionDef
+}
+
 type tickMsg struct{}
 type errMsg struct{ err error }
 type outputMsg struct {
@@ -35,36 +68,74 @@ type outputMsg struct {
 }
 
 type model struct {
-	list        list.Model
-	spin        spinner.Model
-	status      string
-	lastOutput  string
-	loading     bool
-	err         error
-	cancelFunc  context.CancelFunc
+	list       list.Model
+	spin       spinner.Model
+	status     string
+	lastOutput string
+	loading    bool
+	err        error
+	cancelFunc context.CancelFunc
+
+	follow    *logStream
+	followSeq int
+
+	pollCtx    context.Context
+	pollCancel context.CancelFunc
+	polling    map[int]bool
+
+	parsers []ParserRule
+
+	editor *timerEditor
+
+	preferSudoAskpass bool
+	privilege         *privilegeSession
+	privilegeSeq      int
+	privilegeInput    textinput.Model
 }
 
-func newModel() model {
-	items := []list.Item{
-		unitItem{timerName,   "Enable/Disable, View status, Run Now"},
-		unitItem{serviceName, "Run Now, View logs"},
+func newModel(cfg Config) model {
+	items := make([]list.Item, len(cfg.Units))
+	for i, u := range cfg.Units {
+		items[i] = unitItem{title: u.Display, desc: u.Description, cfg: u}
 	}
 	l := list.New(items, list.NewDefaultDelegate(), 50, 10)
-	l.Title = "OneDrive Backup – systemd units"
+	l.Title = "Backup TUI – systemd units"
 	l.SetShowStatusBar(false)
 	l.Styles.Title = lipgloss.NewStyle().Foreground(lipgloss.Color("#00afff")).Bold(true)
 
 	spin := spinner.New()
 	spin.Spinner = spinner.Dot
-	return model{list: l, spin: spin, status: "Ready"}
+	pollCtx, pollCancel := context.WithCancel(context.Background())
+	privilegeInput := textinput.New()
+	privilegeInput.Prompt = "> "
+	privilegeInput.EchoMode = textinput.EchoPassword
+	return model{
+		list:              l,
+		spin:              spin,
+		status:            "Ready",
+		pollCtx:           pollCtx,
+		pollCancel:        pollCancel,
+		polling:           map[int]bool{},
+		parsers:           cfg.Parsers,
+		preferSudoAskpass: cfg.PreferSudoAskpass,
+		privilegeInput:    privilegeInput,
+	}
 }
 
 func main() {
+	configPath := flag.String("config", "", "path to units.toml (default ~/.config/backup_tui/units.toml)")
+	flag.Parse()
+
 	if _, err := exec.LookPath("systemctl"); err != nil {
 		fmt.Fprintln(os.Stderr, "systemctl not found")
 		os.Exit(1)
 	}
-	p := tea.NewProgram(newModel())
+	cfg, err := LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loading config:", err)
+		os.Exit(1)
+	}
+	p := tea.NewProgram(newModel(cfg))
 	if err := p.Start(); err != nil {
 		fmt.Println("Error:", err)
 		os.Exit(1)
@@ -73,7 +144,21 @@ func main() {
 
 // ---------- tea.Model interface ----------
 
-func (m model) Init() tea.Cmd { return nil }
+func (m model) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	for i, item := range m.list.Items() {
+		unit, ok := item.(unitItem)
+		if !ok {
+			continue
+		}
+		interval, err := unit.cfg.PollInterval()
+		if err != nil || interval <= 0 {
+			continue
+		}
+		cmds = append(cmds, scheduleRefresh(i, interval))
+	}
+	return tea.Batch(cmds...)
+}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -81,29 +166,206 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
-			if m.cancelFunc != nil {
-				m.cancelFunc()
+		key := msg.String()
+		if key == "q" || key == "ctrl+c" {
+			if m.editor == nil && m.privilege == nil {
+				if m.follow != nil {
+					m.follow.stop()
+					m.follow = nil
+				}
+				if m.cancelFunc != nil {
+					m.cancelFunc()
+				}
+				m.pollCancel()
+				return m, tea.Quit
+			}
+		}
+		if m.privilege != nil {
+			switch key {
+			case "esc":
+				m.privilege.stop()
+				m.privilege = nil
+				m.privilegeInput.SetValue("")
+				return m, nil
+			case "enter":
+				text := m.privilegeInput.Value()
+				m.privilegeInput.SetValue("")
+				if err := m.privilege.respond(text); err != nil {
+					m.status = "Error: " + err.Error()
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.privilegeInput, cmd = m.privilegeInput.Update(msg)
+			return m, cmd
+		}
+		if m.editor != nil {
+			switch key {
+			case "esc":
+				m.editor = nil
+				return m, nil
+			case "tab":
+				m.editor.focusNext(1)
+				return m, nil
+			case "shift+tab":
+				m.editor.focusNext(-1)
+				return m, nil
+			case "enter":
+				cmd := m.editor.validateAndSave()
+				m.editor.status = "validating…"
+				return m, cmd
+			}
+			var cmd tea.Cmd
+			m.editor.inputs[m.editor.focus], cmd = m.editor.inputs[m.editor.focus].Update(msg)
+			return m, cmd
+		}
+		if key == "e" && m.follow == nil {
+			if selected, ok := m.list.SelectedItem().(unitItem); ok && strings.HasSuffix(selected.cfg.Unit, ".timer") {
+				return m, loadTimerFile(selected.cfg)
+			}
+		}
+		if m.follow != nil {
+			if key == "esc" {
+				m.follow.stop()
+				m.follow = nil
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.follow.vp, cmd = m.follow.vp.Update(msg)
+			return m, cmd
+		}
+		if key == "f" {
+			if selected, ok := m.list.SelectedItem().(unitItem); ok {
+				if m.follow != nil {
+					m.follow.stop()
+				}
+				m.followSeq++
+				ls, cmd := startLogStream(m.followSeq, selected.cfg)
+				m.follow = ls
+				return m, cmd
+			}
+		}
+		if selected, ok := m.list.SelectedItem().(unitItem); ok {
+			if action, ok := selected.cfg.Actions[key]; ok {
+				cmds = append(cmds, m.asyncRun(action, selected.cfg))
+				if action.Verb == "start" {
+					cmds = append(cmds, lookupExecStart(selected.cfg))
+				}
+				return m, tea.Batch(cmds...)
+			}
+		}
+	case escalationNeededMsg:
+		m.loading = false
+		m.privilegeSeq++
+		ps, cmd := startEscalation(m.privilegeSeq, msg.unit, msg.args, msg.action.Post, m.preferSudoAskpass)
+		m.privilege = ps
+		m.privilegeInput.Focus()
+		m.status = "authentication required for " + msg.unit.Unit
+		return m, cmd
+	case privilegeLineMsg:
+		if m.privilege == nil || msg.streamID != m.privilege.id {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			m.privilege.stop()
+			m.privilege = nil
+			return m, nil
+		}
+		if msg.closed {
+			post := m.privilege.post
+			m.status = "done"
+			m.privilege.stop()
+			m.privilege = nil
+			if post != "" {
+				return m, runPostHook(post)
+			}
+			return m, nil
+		}
+		m.privilege.output = append(m.privilege.output, msg.line)
+		return m, m.privilege.waitForLine()
+	case timerFileMsg:
+		if msg.err != nil {
+			m.status = "Error reading " + msg.unit.Unit + ": " + msg.err.Error()
+			return m, nil
+		}
+		m.editor = newTimerEditor(msg.unit, msg.content)
+		return m, nil
+	case calendarCheckMsg:
+		if m.editor != nil {
+			m.editor.status = ""
+			m.editor.err = msg.err
+		}
+		return m, nil
+	case timerSavedMsg:
+		if m.editor != nil {
+			if msg.err != nil {
+				m.editor.status = ""
+				m.editor.err = msg.err
+				return m, nil
+			}
+			m.editor.status = ""
+			m.editor.err = nil
+			m.editor.nextRun = msg.nextRun
+		}
+		m.status = "Timer schedule updated"
+		return m, nil
+	case execStartMsg:
+		if selected, ok := m.list.SelectedItem().(unitItem); ok && selected.cfg.Unit == msg.unit.Unit {
+			if parser := SelectParser(msg.execStart, m.parsers); parser != nil {
+				if m.follow != nil {
+					m.follow.stop()
+				}
+				m.followSeq++
+				ls, cmd := startBackupFollow(m.followSeq, msg.unit, parser)
+				m.follow = ls
+				return m, cmd
 			}
-			return m, tea.Quit
-		case "enter":
-			selected := m.list.SelectedItem().(unitItem)
-			return m, m.asyncRun("status", selected.title)
-		case "r":
-			selected := m.list.SelectedItem().(unitItem)
-			return m, m.asyncRun("start", selected.title)
-		case " ":
-			selected := m.list.SelectedItem().(unitItem)
-			action := "enable"
-			if strings.HasSuffix(selected.title, ".timer") {
-				action = "toggle"
+		}
+		return m, nil
+	case refreshTickMsg:
+		if item, ok := m.list.Items()[msg.index].(unitItem); ok {
+			if interval, err := item.cfg.PollInterval(); err == nil && interval > 0 {
+				cmds = append(cmds, scheduleRefresh(msg.index, interval))
+			}
+			if !m.polling[msg.index] {
+				m.polling[msg.index] = true
+				cmds = append(cmds, pollUnitState(m.pollCtx, msg.index, item.cfg))
 			}
-			return m, m.asyncRun(action, selected.title)
-		case "l":
-			selected := m.list.SelectedItem().(unitItem)
-			return m, m.asyncRun("logs", selected.title)
 		}
+		return m, tea.Batch(cmds...)
+	case stateMsg:
+		m.polling[msg.index] = false
+		if msg.err != nil {
+			return m, nil
+		}
+		if item, ok := m.list.Items()[msg.index].(unitItem); ok {
+			item.desc = msg.state.describe()
+			cmds = append(cmds, m.list.SetItem(msg.index, item))
+		}
+		return m, tea.Batch(cmds...)
+	case logLineMsg:
+		if m.follow == nil || msg.streamID != m.follow.id {
+			return m, nil
+		}
+		if msg.closed {
+			m.follow.stop()
+			return m, nil
+		}
+		if barCmd := m.follow.appendLine(msg.line); barCmd != nil {
+			cmds = append(cmds, barCmd)
+		}
+		cmds = append(cmds, m.follow.waitForLine())
+		return m, tea.Batch(cmds...)
+	case progress.FrameMsg:
+		if m.follow == nil || m.follow.parser == nil {
+			return m, nil
+		}
+		bar, cmd := m.follow.bar.Update(msg)
+		if b, ok := bar.(progress.Model); ok {
+			m.follow.bar = b
+		}
+		return m, cmd
 	case outputMsg:
 		m.loading = false
 		m.status = fmt.Sprintf("[%s] %s", msg.tag, firstLine(msg.out))
@@ -129,7 +391,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
-	var cmd list.Cmd
+	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
 	cmds = append(cmds, cmd)
 
@@ -137,6 +399,31 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) View() string {
+	if m.editor != nil {
+		return m.editor.View()
+	}
+	if m.privilege != nil {
+		var b strings.Builder
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#d7af00")).Bold(true).
+			Render("Authentication required"))
+		b.WriteString("\n\n")
+		b.WriteString(strings.Join(m.privilege.output, "\n"))
+		b.WriteString("\n\n" + m.privilegeInput.View())
+		b.WriteString("\n\n[enter] send • [esc] cancel\n")
+		return b.String()
+	}
+	if m.follow != nil {
+		var b strings.Builder
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#00afff")).Bold(true).Render("Following " + m.follow.unit))
+		b.WriteString("\n\n")
+		if stats := m.follow.statsLine(); stats != "" {
+			b.WriteString(stats + "\n\n")
+		}
+		b.WriteString(m.follow.vp.View())
+		b.WriteString("\n\n[esc] stop following • [↑/↓] scroll • [q] quit\n")
+		return b.String()
+	}
+
 	var b strings.Builder
 	b.WriteString(m.list.View())
 	if m.loading {
@@ -148,50 +435,120 @@ func (m model) View() string {
 	if m.lastOutput != "" {
 		b.WriteString("\n\n" + lipgloss.NewStyle().Faint(true).Render(trimLines(m.lastOutput, 20)))
 	}
-	b.WriteString("\n\n[↑/↓] navigate • [space] enable/disable • [r] run • [enter] status • [l] logs • [q] quit\n")
+	b.WriteString("\n\n[↑/↓] navigate • [space] enable/disable • [r] run • [enter] status • [l] logs • [f] follow • [e] edit timer • [q] quit\n")
 	return b.String()
 }
 
 // ---------- helpers ----------
 
-func (m *model) asyncRun(tag, unit string) tea.Cmd {
+// asyncRun executes a single configured action against unit, running its
+// pre/post shell hooks (if any) around the systemctl invocation itself.
+func (m *model) asyncRun(action ActionDef, unit UnitConfig) tea.Cmd {
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancelFunc = cancel
 	m.loading = true
-	m.status = tag + " " + unit
+	m.status = action.Verb + " " + unit.Unit
 	return tea.Batch(func() tea.Msg { return tickMsg{} }, func() tea.Msg {
-		var cmd *exec.Cmd
-		switch tag {
-		case "status":
-			cmd = exec.CommandContext(ctx, "systemctl", "status", "--no-pager", unit)
-		case "start":
-			cmd = exec.CommandContext(ctx, "systemctl", "start", unit)
-		case "toggle":
-			// enable --now if disabled, disable if enabled
-			stateOut, _ := exec.Command("systemctl", "is-enabled", unit).Output()
-			if strings.TrimSpace(string(stateOut)) == "enabled" {
-				cmd = exec.CommandContext(ctx, "systemctl", "disable", "--now", unit)
-			} else {
-				cmd = exec.CommandContext(ctx, "systemctl", "enable", "--now", unit)
+		var buf bytes.Buffer
+
+		if action.Pre != "" {
+			if err := runHook(ctx, action.Pre, &buf); err != nil {
+				return errMsg{fmt.Errorf("pre hook: %w", err)}
 			}
-		case "enable":
-			cmd = exec.CommandContext(ctx, "systemctl", "enable", "--now", unit)
-		case "logs":
-			cmd = exec.CommandContext(ctx, "journalctl", "-u", unit, "-n", "50", "--no-pager")
-		default:
-			return errMsg{fmt.Errorf("unknown action")}
 		}
-		var buf bytes.Buffer
+
+		cmd, verbArgs, err := systemctlCmd(ctx, unit, action.Verb)
+		if err != nil {
+			return errMsg{err}
+		}
 		cmd.Stdout = &buf
 		cmd.Stderr = &buf
-		err := cmd.Run()
-		if err != nil {
+		if err := cmd.Run(); err != nil {
+			if verbArgs != nil && needsEscalation(buf.Bytes(), err) {
+				return escalationNeededMsg{unit: unit, args: verbArgs, action: action}
+			}
 			return errMsg{err}
 		}
-		return outputMsg{tag: tag, out: buf.String()}
+
+		if action.Post != "" {
+			if err := runHook(ctx, action.Post, &buf); err != nil {
+				return errMsg{fmt.Errorf("post hook: %w", err)}
+			}
+		}
+
+		return outputMsg{tag: action.Verb, out: buf.String()}
 	})
 }
 
+func runHook(ctx context.Context, script string, out *bytes.Buffer) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", script)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// runPostHook runs an action's post-run hook once an escalated systemctl
+// retry has finished, mirroring the post hook asyncRun runs on its
+// un-escalated success path.
+func runPostHook(script string) tea.Cmd {
+	return func() tea.Msg {
+		var buf bytes.Buffer
+		if err := runHook(context.Background(), script, &buf); err != nil {
+			return errMsg{fmt.Errorf("post hook: %w", err)}
+		}
+		return outputMsg{tag: "post hook", out: buf.String()}
+	}
+}
+
+// systemctlCmd builds the systemctl invocation for a single verb against
+// unit, adding --user when the unit is scoped to the user session. It also
+// returns the bare verb args (without --user) so a failed call can be
+// retried through escalatedCmd with the same arguments.
+func systemctlCmd(ctx context.Context, unit UnitConfig, verb string) (*exec.Cmd, []string, error) {
+	if verb == "logs" {
+		logArgs := []string{}
+		if unit.Scope == ScopeUser {
+			logArgs = append(logArgs, "--user")
+		}
+		logArgs = append(logArgs, "-u", unit.Unit, "-n", "50", "--no-pager")
+		return exec.CommandContext(ctx, "journalctl", logArgs...), nil, nil
+	}
+
+	verbArgs, err := systemctlVerbArgs(unit, verb)
+	if err != nil {
+		return nil, nil, err
+	}
+	args := verbArgs
+	if unit.Scope == ScopeUser {
+		args = append([]string{"--user"}, verbArgs...)
+	}
+	return exec.CommandContext(ctx, "systemctl", args...), verbArgs, nil
+}
+
+func systemctlVerbArgs(unit UnitConfig, verb string) ([]string, error) {
+	switch verb {
+	case "status":
+		return []string{"status", "--no-pager", unit.Unit}, nil
+	case "start":
+		return []string{"start", unit.Unit}, nil
+	case "toggle":
+		stateArgs := []string{}
+		if unit.Scope == ScopeUser {
+			stateArgs = append(stateArgs, "--user")
+		}
+		stateArgs = append(stateArgs, "is-enabled", unit.Unit)
+		stateOut, _ := exec.Command("systemctl", stateArgs...).Output()
+		if strings.TrimSpace(string(stateOut)) == "enabled" {
+			return []string{"disable", "--now", unit.Unit}, nil
+		}
+		return []string{"enable", "--now", unit.Unit}, nil
+	case "enable":
+		return []string{"enable", "--now", unit.Unit}, nil
+	default:
+		return nil, fmt.Errorf("unknown action %q", verb)
+	}
+}
+
 func firstLine(s string) string {
 	if i := strings.IndexByte(s, '\n'); i >= 0 {
 		return s[:i]
@@ -205,5 +562,3 @@ func trimLines(s string, n int) string {
 	}
 	return strings.Join(lines, "\n")
 }
-
-type tea = bubbletea
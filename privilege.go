@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/creack/pty"
+)
+
+// authRequiredMarkers are substrings systemctl prints when the calling
+// user lacks permission and polkit needs to prompt interactively.
+var authRequiredMarkers = []string{
+	"interactive authentication required",
+	"authentication is required",
+	"permission denied",
+}
+
+// needsEscalation reports whether a failed systemctl invocation looks
+// like a permissions problem polkit (or sudo) could resolve, rather than
+// a genuine failure (bad unit name, already in that state, ...).
+func needsEscalation(out []byte, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, syscall.EACCES) {
+		return true
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	lower := strings.ToLower(string(out))
+	for _, marker := range authRequiredMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// escalatedCmd builds the privileged retry of a systemctl invocation: user
+// units never need root, so they're simply re-run with --user; system
+// units go through pkexec, or "sudo -A" when preferSudoAskpass is set and
+// SUDO_ASKPASS is present in the environment.
+func escalatedCmd(ctx context.Context, unit UnitConfig, args []string, preferSudoAskpass bool) *exec.Cmd {
+	if unit.Scope == ScopeUser {
+		return exec.CommandContext(ctx, "systemctl", append([]string{"--user"}, args...)...)
+	}
+	if preferSudoAskpass && os.Getenv("SUDO_ASKPASS") != "" {
+		return exec.CommandContext(ctx, "sudo", append([]string{"-A", "systemctl"}, args...)...)
+	}
+	return exec.CommandContext(ctx, "pkexec", append([]string{"systemctl"}, args...)...)
+}
+
+// privilegeLineMsg streams a line of output from an escalated pkexec/sudo
+// session back into the TUI, tagged by stream like logLineMsg, so a
+// polkit text challenge renders inline instead of corrupting the
+// alt-screen.
+type privilegeLineMsg struct {
+	streamID int
+	line     string
+	closed   bool
+	err      error
+}
+
+// privilegeSession wraps an escalated systemctl retry running under a
+// pty, mirroring logStream's channel/cancel pattern so the rest of the
+// model can treat it the same way.
+type privilegeSession struct {
+	id     int
+	cancel context.CancelFunc
+	pty    *os.File
+	lines  chan privilegeLineMsg
+	output []string
+
+	// post is the original action's post-run hook, if any, carried over
+	// so it still fires once the escalated retry finishes cleanly.
+	post string
+}
+
+// startEscalation re-runs a systemctl verb with elevated privilege,
+// attached to a pty so an interactive polkit (or sudo askpass) prompt can
+// be captured and rendered rather than left to fight with the alt-screen.
+func startEscalation(id int, unit UnitConfig, args []string, post string, preferSudoAskpass bool) (*privilegeSession, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ps := &privilegeSession{id: id, cancel: cancel, lines: make(chan privilegeLineMsg, 64), post: post}
+
+	cmd := escalatedCmd(ctx, unit, args, preferSudoAskpass)
+	f, err := pty.Start(cmd)
+	if err != nil {
+		cancel()
+		return ps, func() tea.Msg {
+			return privilegeLineMsg{streamID: id, err: err, closed: true}
+		}
+	}
+	ps.pty = f
+
+	// This goroutine is lines' only writer, so it alone closes the
+	// channel once it's done sending — on any exit path, including a
+	// cancelled ctx — rather than leaving waitForLine's pending receive
+	// racing ctx.Done() and potentially blocking forever.
+	go func() {
+		defer close(ps.lines)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			select {
+			case ps.lines <- privilegeLineMsg{streamID: id, line: scanner.Text()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := cmd.Wait(); err != nil {
+			select {
+			case ps.lines <- privilegeLineMsg{streamID: id, err: err, closed: true}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return ps, ps.waitForLine()
+}
+
+// waitForLine returns a tea.Cmd that blocks for the next output line;
+// Update must call it again after each privilegeLineMsg until closed. A
+// closed channel (the stream ended, or stop() cancelled it) unblocks this
+// receive deterministically and is reported as closed:true.
+func (ps *privilegeSession) waitForLine() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ps.lines
+		if !ok {
+			return privilegeLineMsg{streamID: ps.id, closed: true}
+		}
+		return msg
+	}
+}
+
+// respond forwards one line of user input (a password, a "yes", ...) to
+// the pty driving the escalated command, echoing a masked placeholder
+// into the transcript rather than the real input.
+func (ps *privilegeSession) respond(text string) error {
+	ps.output = append(ps.output, strings.Repeat("*", len(text)))
+	_, err := ps.pty.Write([]byte(text + "\n"))
+	return err
+}
+
+// stop cancels the escalated command and closes its pty.
+func (ps *privilegeSession) stop() {
+	if ps.cancel != nil {
+		ps.cancel()
+	}
+	if ps.pty != nil {
+		_ = ps.pty.Close()
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+)
+
+// refreshTickMsg fires on a unit's own update_interval and requests a
+// fresh systemctl show poll for it.
+type refreshTickMsg struct{ index int }
+
+// stateMsg delivers the result of one poll back into the model.
+type stateMsg struct {
+	index int
+	state unitState
+	err   error
+}
+
+// unitState is the subset of "systemctl show" properties the dashboard
+// renders in the item description.
+type unitState struct {
+	ActiveState   string
+	SubState      string
+	LoadState     string
+	UnitFileState string
+	NextElapse    time.Time
+}
+
+// scheduleRefresh returns the tea.Cmd that waits out a unit's poll
+// interval and then requests another refreshTickMsg for it.
+func scheduleRefresh(index int, interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return refreshTickMsg{index: index}
+	})
+}
+
+// pollUnitState runs a single "systemctl show" for unit and parses the
+// result. It's issued per refreshTickMsg and debounced by the model (one
+// in-flight poll per unit at a time), so a slow host never piles up
+// subprocesses for a unit that ticks faster than it can be queried.
+func pollUnitState(ctx context.Context, index int, unit UnitConfig) tea.Cmd {
+	return func() tea.Msg {
+		args := []string{}
+		if unit.Scope == ScopeUser {
+			args = append(args, "--user")
+		}
+		args = append(args, "show", unit.Unit,
+			"--property=ActiveState,SubState,LoadState,UnitFileState,NextElapseUSecRealtime")
+
+		out, err := exec.CommandContext(ctx, "systemctl", args...).Output()
+		if err != nil {
+			return stateMsg{index: index, err: err}
+		}
+		return stateMsg{index: index, state: parseUnitState(string(out))}
+	}
+}
+
+func parseUnitState(out string) unitState {
+	var s unitState
+	for _, line := range strings.Split(out, "\n") {
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "ActiveState":
+			s.ActiveState = val
+		case "SubState":
+			s.SubState = val
+		case "LoadState":
+			s.LoadState = val
+		case "UnitFileState":
+			s.UnitFileState = val
+		case "NextElapseUSecRealtime":
+			if usec, err := strconv.ParseInt(val, 10, 64); err == nil && usec > 0 {
+				s.NextElapse = time.UnixMicro(usec)
+			}
+		}
+	}
+	return s
+}
+
+// describe renders the status line shown as the list item's Description.
+func (s unitState) describe() string {
+	icon := "○"
+	if s.ActiveState == "active" {
+		icon = "●"
+	}
+	line := fmt.Sprintf("%s %s (%s)", icon, s.ActiveState, s.SubState)
+	switch {
+	case !s.NextElapse.IsZero():
+		line += " • next run in " + time.Until(s.NextElapse).Round(time.Minute).String()
+	case s.UnitFileState != "":
+		line += " • " + s.UnitFileState
+	}
+	return line
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The field* constants index the three drop-in settings this editor exposes.
+const (
+	fieldOnCalendar = iota
+	fieldPersistent
+	fieldRandomizedDelay
+	fieldCount
+)
+
+var timerFieldKeys = [fieldCount]string{
+	fieldOnCalendar:      "OnCalendar",
+	fieldPersistent:      "Persistent",
+	fieldRandomizedDelay: "RandomizedDelaySec",
+}
+
+// timerEditor is the bubbles/textinput form opened by pressing "e" on a
+// .timer item.
+type timerEditor struct {
+	unit    UnitConfig
+	inputs  [fieldCount]textinput.Model
+	focus   int
+	status  string
+	nextRun []string
+	err     error
+}
+
+// timerFileMsg carries the result of "systemctl cat <timer>", used to
+// seed the editor with the unit's current values.
+type timerFileMsg struct {
+	unit    UnitConfig
+	content string
+	err     error
+}
+
+// calendarCheckMsg carries a failed systemd-analyze validation; a
+// successful validation goes straight on to writing the override and is
+// reported via timerSavedMsg instead.
+type calendarCheckMsg struct{ err error }
+
+// timerSavedMsg reports whether the drop-in override was written and
+// systemd reloaded, plus the next few times the validated expression will
+// elapse.
+type timerSavedMsg struct {
+	nextRun []string
+	err     error
+}
+
+// loadTimerFile shells out to "systemctl cat" to read a timer's current
+// unit file so the editor can be pre-filled with its live values.
+func loadTimerFile(unit UnitConfig) tea.Cmd {
+	return func() tea.Msg {
+		args := []string{}
+		if unit.Scope == ScopeUser {
+			args = append(args, "--user")
+		}
+		args = append(args, "cat", unit.Unit)
+		out, err := exec.Command("systemctl", args...).CombinedOutput()
+		return timerFileMsg{unit: unit, content: string(out), err: err}
+	}
+}
+
+// newTimerEditor builds the form from a unit file's [Timer] section,
+// defaulting any field the file doesn't set.
+func newTimerEditor(unit UnitConfig, content string) *timerEditor {
+	values := parseTimerSection(content)
+	e := &timerEditor{unit: unit}
+	for i, key := range timerFieldKeys {
+		ti := textinput.New()
+		ti.Prompt = key + "= "
+		ti.SetValue(values[key])
+		e.inputs[i] = ti
+	}
+	e.inputs[0].Focus()
+	return e
+}
+
+func parseTimerSection(content string) map[string]string {
+	values := map[string]string{
+		"OnCalendar":         "",
+		"Persistent":         "false",
+		"RandomizedDelaySec": "0",
+	}
+	inTimer := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inTimer = line == "[Timer]"
+			continue
+		}
+		if !inTimer {
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if _, known := values[strings.TrimSpace(key)]; known {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+	return values
+}
+
+func (e *timerEditor) value(field int) string { return e.inputs[field].Value() }
+
+// focusNext cycles focus between the form's inputs.
+func (e *timerEditor) focusNext(delta int) {
+	e.inputs[e.focus].Blur()
+	e.focus = (e.focus + delta + fieldCount) % fieldCount
+	e.inputs[e.focus].Focus()
+}
+
+// validateAndSave checks the OnCalendar expression with systemd-analyze,
+// then, if valid, writes the drop-in and reloads systemd.
+func (e *timerEditor) validateAndSave() tea.Cmd {
+	unit, expr := e.unit, e.value(fieldOnCalendar)
+	persistent, delay := e.value(fieldPersistent), e.value(fieldRandomizedDelay)
+	return func() tea.Msg {
+		out, err := exec.Command("systemd-analyze", "calendar", "--iterations=3", expr).CombinedOutput()
+		if err != nil {
+			return calendarCheckMsg{err: fmt.Errorf("%s: %s", err, strings.TrimSpace(string(out)))}
+		}
+		next := parseNextElapses(string(out))
+
+		if err := writeTimerOverride(unit, expr, persistent, delay); err != nil {
+			return timerSavedMsg{err: err}
+		}
+
+		reloadArgs := []string{}
+		if unit.Scope == ScopeUser {
+			reloadArgs = append(reloadArgs, "--user")
+		}
+		reloadArgs = append(reloadArgs, "daemon-reload")
+		if out, err := exec.Command("systemctl", reloadArgs...).CombinedOutput(); err != nil {
+			return timerSavedMsg{err: fmt.Errorf("daemon-reload: %w: %s", err, out)}
+		}
+		return timerSavedMsg{nextRun: next}
+	}
+}
+
+// parseNextElapses pulls the "Next elapse: ..." line and the "Iter. #N: ..."
+// lines systemd-analyze prints with --iterations out of its calendar
+// validation output.
+func parseNextElapses(out string) []string {
+	var next []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Iter.") || strings.HasPrefix(line, "Next elapse:") {
+			next = append(next, line)
+		}
+	}
+	return next
+}
+
+// writeTimerOverride writes a drop-in override.conf for unit containing
+// the edited [Timer] values, under /etc/systemd/system (system scope) or
+// the user equivalent.
+func writeTimerOverride(unit UnitConfig, onCalendar, persistent, randomizedDelay string) error {
+	dir, err := dropInDir(unit)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	contents := fmt.Sprintf("[Timer]\nOnCalendar=\nOnCalendar=%s\nPersistent=%s\nRandomizedDelaySec=%s\n",
+		onCalendar, persistent, randomizedDelay)
+	return os.WriteFile(filepath.Join(dir, "override.conf"), []byte(contents), 0o644)
+}
+
+// View renders the edit form: one line per input, then validation status
+// or the next few elapse times once saved.
+func (e *timerEditor) View() string {
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#00afff")).Bold(true).
+		Render("Edit " + e.unit.Unit))
+	b.WriteString("\n\n")
+	for _, in := range e.inputs {
+		b.WriteString(in.View() + "\n")
+	}
+	b.WriteRune('\n')
+	switch {
+	case e.err != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#d75f5f")).Render(e.err.Error()))
+	case e.status != "":
+		b.WriteString(e.status)
+	case len(e.nextRun) > 0:
+		b.WriteString(strings.Join(e.nextRun, "\n"))
+	}
+	b.WriteString("\n\n[tab] next field • [enter] validate & save • [esc] cancel\n")
+	return b.String()
+}
+
+func dropInDir(unit UnitConfig) (string, error) {
+	if unit.Scope == ScopeUser {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".config", "systemd", "user", unit.Unit+".d"), nil
+	}
+	return filepath.Join("/etc/systemd/system", unit.Unit+".d"), nil
+}